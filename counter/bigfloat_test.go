@@ -0,0 +1,98 @@
+package counter
+
+import (
+	"math/big"
+	"testing"
+)
+
+func bigf(f float64) *big.Float { return big.NewFloat(f) }
+
+// TestGetReturnsIndependentBigFloat reproduces the aliasing bug where
+// mutating a *big.Float returned by Get - the idiomatic way to use
+// big.Float - used to corrupt the counter's shared Base and therefore
+// every other unset key.
+func TestGetReturnsIndependentBigFloat(t *testing.T) {
+	c := New[*big.Float](bigf(0))
+
+	v := c.Get("missing")
+	v.Add(v, bigf(100))
+
+	if got := c.Get("other-missing"); got.Cmp(bigf(0)) != 0 {
+		t.Fatalf("Get(other-missing) after mutating a prior Get() result = %v, want 0", got)
+	}
+}
+
+func TestGetReturnsIndependentBigFloatDense(t *testing.T) {
+	c := New[*big.Float](bigf(0))
+	c.Densify()
+
+	v := c.Get("missing")
+	v.Add(v, bigf(100))
+
+	if got := c.Get("other-missing"); got.Cmp(bigf(0)) != 0 {
+		t.Fatalf("Get(other-missing) on dense backend after mutating a prior Get() result = %v, want 0", got)
+	}
+}
+
+func TestSetDoesNotAliasCallersBigFloat(t *testing.T) {
+	c := New[*big.Float](bigf(0))
+
+	v := bigf(5)
+	c.Set("a", v)
+	v.Add(v, bigf(100))
+
+	if got := c.Get("a"); got.Cmp(bigf(5)) != 0 {
+		t.Fatalf("Get(a) after caller mutated its own *big.Float post-Set = %v, want 5", got)
+	}
+}
+
+func TestBigFloatSparseBackend(t *testing.T) {
+	c := New[*big.Float](bigf(0))
+	c.Set("a", bigf(1))
+	c.Set("b", bigf(3))
+
+	if got := c.Get("a"); got.Cmp(bigf(1)) != 0 {
+		t.Fatalf("Get(a) = %v, want 1", got)
+	}
+
+	sum := Add(c, c)
+	if got := sum.Get("a"); got.Cmp(bigf(2)) != 0 {
+		t.Fatalf("Add(c, c).Get(a) = %v, want 2", got)
+	}
+
+	key, val := c.ArgMax()
+	if key != "b" || val.Cmp(bigf(3)) != 0 {
+		t.Fatalf("ArgMax() = %q, %v; want %q, 3", key, val, "b")
+	}
+
+	c.Normalize()
+	if got := c.Sum(); got.Cmp(bigf(1)) != 0 {
+		t.Fatalf("Sum() after Normalize = %v, want 1", got)
+	}
+}
+
+func TestBigFloatDenseBackend(t *testing.T) {
+	c := New[*big.Float](bigf(0))
+	c.Set("a", bigf(1))
+	c.Set("b", bigf(3))
+	c.Densify()
+
+	if got := c.Get("a"); got.Cmp(bigf(1)) != 0 {
+		t.Fatalf("Get(a) after Densify = %v, want 1", got)
+	}
+
+	sum := Add(c, c)
+	if got := sum.Get("b"); got.Cmp(bigf(6)) != 0 {
+		t.Fatalf("Add(c, c).Get(b) = %v, want 6", got)
+	}
+
+	key, val := c.ArgMax()
+	if key != "b" || val.Cmp(bigf(3)) != 0 {
+		t.Fatalf("ArgMax() = %q, %v; want %q, 3", key, val, "b")
+	}
+
+	c.Normalize()
+	if got := c.Sum(); got.Cmp(bigf(1)) != 0 {
+		t.Fatalf("Sum() after Normalize = %v, want 1", got)
+	}
+}
@@ -3,88 +3,247 @@ package counter
 import "gnlp"
 import "math"
 
-type Counter struct {
-	values map[string]float64
+// denseThreshold is the tracked-key count past which a Counter
+// automatically switches from its map backend to the dense,
+// interned-id backend (see Densify)
+const denseThreshold = 1 << 16
+
+type Counter[V Numeric] struct {
+	values map[string]V // sparse backend; nil once densified
+	dense  []V          // dense backend; nil until Densify
+	dict   *StringDict  // key<->id mapping for the dense backend
+
 	// default value for missing items
-	Base float64
+	Base V
+
+	// Treat values as log-probabilities when sampling (see sample.go).
+	// Only meaningful for Counter[float64].
+	LogSpace bool
+
+	// Maps a key to its position in an ordered sequence (timestamp,
+	// sentence index, token offset, ...) for SmoothKZ/SmoothKZA. If
+	// nil, keys are parsed as float64 (see smooth.go). Only meaningful
+	// for Counter[float64].
+	KeyOrder func(string) float64
+
+	// If set, SmoothKZ/SmoothKZA treat integer positions with no
+	// tracked key as Base instead of skipping them
+	FillBase bool
+
+	// Lazily built alias table for O(1) sampling; invalidated on write.
+	// Used by Sample/SampleN (see sample.go).
+	alias aliasTable
 }
 
-func New(base float64) *Counter {
-	return &Counter{make(map[string]float64), base}
+func New[V Numeric](base V) *Counter[V] {
+	return &Counter[V]{values: make(map[string]V), Base: numCopy(base)}
 }
 
-// Return a value for a key (falling back to the default)
-func (c *Counter) Get(k string) float64 {
-	v, ok := c.values[k]
+// Intern shares dict's id space with this counter, so element-wise ops
+// against other counters interned against the same dict can run a
+// single indexed loop instead of merging key sets. If the counter has
+// already switched to the dense backend, its values are remapped into
+// dict's id space instead of being silently orphaned behind the old ids.
+func (c *Counter[V]) Intern(dict *StringDict) {
+	if c.dense == nil {
+		c.dict = dict
+		return
+	}
+
+	old, oldDict := c.dense, c.dict
+	c.dict = dict
+	c.dense = make([]V, dict.Len())
+	for i := range c.dense {
+		c.dense[i] = numCopy(c.Base)
+	}
+
+	for id, v := range old {
+		if numEqual(v, c.Base) {
+			continue
+		}
+		newID := dict.Intern(oldDict.Key(uint32(id)))
+		c.growDense(newID)
+		c.dense[newID] = v
+	}
+}
+
+// Densify switches the counter from its map backend to a dense slice
+// indexed by interned id. For large vocabularies this makes
+// Add/Subtract/Multiply/Divide/Apply/Sum/ArgMax iterate contiguous
+// memory instead of map buckets.
+func (c *Counter[V]) Densify() {
+	if c.dense != nil {
+		return
+	}
 
+	if c.dict == nil {
+		c.dict = NewStringDict()
+	}
+	for k := range c.values {
+		c.dict.Intern(k)
+	}
+
+	dense := make([]V, c.dict.Len())
+	for i := range dense {
+		dense[i] = numCopy(c.Base)
+	}
+	for k, v := range c.values {
+		dense[c.dict.Intern(k)] = v
+	}
+
+	c.dense = dense
+	c.values = nil
+}
+
+// growDense extends the dense backend so id is addressable
+func (c *Counter[V]) growDense(id uint32) {
+	for uint32(len(c.dense)) <= id {
+		c.dense = append(c.dense, numCopy(c.Base))
+	}
+}
+
+// Return a value for a key (falling back to the default). The result is
+// always a copy: for *big.Float, mutating it in place (the idiomatic way
+// to use big.Float) never reaches the Counter's internal storage or its
+// shared Base.
+func (c *Counter[V]) Get(k string) V {
+	if c.dense != nil {
+		id, ok := c.dict.Lookup(k)
+		if !ok || int(id) >= len(c.dense) {
+			return numCopy(c.Base)
+		}
+		return numCopy(c.dense[id])
+	}
+
+	v, ok := c.values[k]
 	if ok {
-		return v
+		return numCopy(v)
 	}
-	return c.Base
+	return numCopy(c.Base)
 }
 
-// Set a value for a key
-func (c *Counter) Set(k string, v float64) {
-	if v == c.Base {
+// Set a value for a key. v is copied into the counter's storage, so a
+// caller that keeps mutating its own v in place afterward (as with
+// *big.Float) can't reach back into the counter.
+func (c *Counter[V]) Set(k string, v V) {
+	c.alias.valid = false
+	v = numCopy(v)
+
+	if c.dense != nil {
+		id := c.dict.Intern(k)
+		c.growDense(id)
+		c.dense[id] = v
+		return
+	}
+
+	if numEqual(v, c.Base) {
 		// remove the key
-		c.values[k] = v, false
+		delete(c.values, k)
+	} else {
+		c.values[k] = v
 	}
 
-	c.values[k] = v
+	if len(c.values) > denseThreshold {
+		c.Densify()
+	}
 }
 
 // Increment a value
-func (c *Counter) Incr(k string) {
+func (c *Counter[V]) Incr(k string) {
 	v := c.Get(k)
-	c.Set(k, v+1)
+	c.Set(k, numAdd(v, numOne[V]()))
 }
 
-// Return a list of keys for this counter
-func (c *Counter) Keys() []string {
-	result := make([]string, 0, len(c.values))
+// entry is a tracked (key, value) pair, used internally so callers that
+// need both don't pay a second lookup to recover the value behind a key
+// returned from Keys()
+type entry[V Numeric] struct {
+	key string
+	val V
+}
+
+// entries returns the counter's tracked (key, value) pairs in a single
+// pass over whichever backend is active
+func (c *Counter[V]) entries() []entry[V] {
+	if c.dense != nil {
+		result := make([]entry[V], 0, len(c.dense))
+		for id, v := range c.dense {
+			if numEqual(v, c.Base) {
+				continue
+			}
+			result = append(result, entry[V]{c.dict.Key(uint32(id)), v})
+		}
+		return result
+	}
+
+	result := make([]entry[V], 0, len(c.values))
 
 	for k, v := range c.values {
 		// Don't track default values
-		if v == c.Base {
+		if numEqual(v, c.Base) {
 			continue
 		}
 
-		result = append(result, k)
+		result = append(result, entry[V]{k, v})
 	}
 
 	return result
 }
 
-// Combine two sets of keys w/o duplicates
-func mergeKeys(a, b []string) <-chan string {
-	out := make(chan string)
+// Return a list of keys for this counter
+func (c *Counter[V]) Keys() []string {
+	entries := c.entries()
+	result := make([]string, len(entries))
 
-	go func(out chan<- string) {
-		defer close(out)
+	for i, e := range entries {
+		result[i] = e.key
+	}
 
-		seen := make(map[string]bool)
+	return result
+}
 
-		for _, k := range a {
-			out <- k
-			seen[k] = true
-		}
+// Combine two sets of keys w/o duplicates
+func mergeKeys(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	result := make([]string, 0, len(a)+len(b))
 
-		for _, k := range b {
-			if !seen[k] {
-				out <- k
-			}
+	for _, k := range a {
+		result = append(result, k)
+		seen[k] = true
+	}
+
+	for _, k := range b {
+		if !seen[k] {
+			result = append(result, k)
 		}
-	}(out)
+	}
 
-	return out
+	return result
 }
 
-// Apply an operation on two counters, returning new counter with keys
-// defined by the keys function
-func operate(a, b *Counter, op func(a, b float64) float64, keys func(a, b []string) <-chan string) *Counter {
-	result := New(op(a.Base, b.Base))
+// Apply an operation on two counters, returning new counter over the
+// union of both counters' keys (see mergeKeys). When both counters are densified
+// against the same dict, this runs a single indexed loop instead of
+// merging key sets.
+func operate[V Numeric](a, b *Counter[V], op func(a, b V) V) *Counter[V] {
+	result := New[V](op(a.Base, b.Base))
+
+	if a.dense != nil && b.dense != nil && a.dict == b.dict {
+		n := a.dict.Len()
+		for id := 0; id < n; id++ {
+			av, bv := a.Base, b.Base
+			if id < len(a.dense) {
+				av = a.dense[id]
+			}
+			if id < len(b.dense) {
+				bv = b.dense[id]
+			}
+			result.Set(a.dict.Key(uint32(id)), op(av, bv))
+		}
+		return result
+	}
 
-	for k := range keys(a.Keys(), b.Keys()) {
+	for _, k := range mergeKeys(a.Keys(), b.Keys()) {
 		result.Set(k, op(a.Get(k), b.Get(k)))
 	}
 
@@ -92,93 +251,125 @@ func operate(a, b *Counter, op func(a, b float64) float64, keys func(a, b []stri
 }
 
 // Add a to b, returning a new counter
-func Add(a, b *Counter) *Counter {
-	return operate(a, b, func(a, b float64) float64 { return a + b }, mergeKeys)
+func Add[V Numeric](a, b *Counter[V]) *Counter[V] {
+	return operate(a, b, numAdd[V])
 }
 
 // Subtract b from a, returning a new counter
-func Subtract(a, b *Counter) *Counter {
-	return operate(a, b, func(a, b float64) float64 { return a - b }, mergeKeys)
+func Subtract[V Numeric](a, b *Counter[V]) *Counter[V] {
+	return operate(a, b, numSub[V])
 }
 
 // Multiply a by b, returning a new counter
-func Multiply(a, b *Counter) *Counter {
-	return operate(a, b, func(a, b float64) float64 { return a * b }, mergeKeys)
+func Multiply[V Numeric](a, b *Counter[V]) *Counter[V] {
+	return operate(a, b, numMul[V])
 }
 
 // Divide a by b, returning a new counter
-func Divide(a, b *Counter) *Counter {
-	return operate(a, b, func(a, b float64) float64 { return a / b }, mergeKeys)
+func Divide[V Numeric](a, b *Counter[V]) *Counter[V] {
+	return operate(a, b, numDiv[V])
 }
 
-// Apply an operation on two counters, updating the first counter with keys
-// defined by the keys function
-func (a *Counter) operate(b *Counter, op func(a, b float64) float64, keys func(a, b []string) <-chan string) {
+// Apply an operation on two counters, updating the first counter over
+// the union of both counters' keys (see mergeKeys). When both counters are densified
+// against the same dict, this runs a single indexed loop instead of
+// merging key sets.
+func (a *Counter[V]) operate(b *Counter[V], op func(a, b V) V) {
+	if a.dense != nil && b.dense != nil && a.dict == b.dict {
+		a.alias.valid = false
+
+		// Grow with the pre-update Base: every padded id still stands
+		// in for a.Base until the loop below applies op to it.
+		n := a.dict.Len()
+		if n > 0 {
+			a.growDense(uint32(n - 1))
+		}
+		for id := 0; id < n; id++ {
+			bv := b.Base
+			if id < len(b.dense) {
+				bv = b.dense[id]
+			}
+			a.dense[id] = op(a.dense[id], bv)
+		}
+
+		a.Base = op(a.Base, b.Base)
+		return
+	}
+
 	a.Base = op(a.Base, b.Base)
 
-	for k := range keys(a.Keys(), b.Keys()) {
+	for _, k := range mergeKeys(a.Keys(), b.Keys()) {
 		a.Set(k, op(a.Get(k), b.Get(k)))
 	}
 }
 
 // Add o to c
-func (c *Counter) Add(o *Counter) {
-	c.operate(o, func(a, b float64) float64 { return a + b }, mergeKeys)
+func (c *Counter[V]) Add(o *Counter[V]) {
+	c.operate(o, numAdd[V])
 }
 
 // Subtract o from c
-func (c *Counter) Subtract(o *Counter) {
-	c.operate(o, func(a, b float64) float64 { return a - b }, mergeKeys)
+func (c *Counter[V]) Subtract(o *Counter[V]) {
+	c.operate(o, numSub[V])
 }
 
 // Multiply c by o
-func (c *Counter) Multiply(o *Counter) {
-	c.operate(o, func(a, b float64) float64 { return a * b }, mergeKeys)
+func (c *Counter[V]) Multiply(o *Counter[V]) {
+	c.operate(o, numMul[V])
 }
 
 // Divide c by o
-func (c *Counter) Divide(o *Counter) {
-	c.operate(o, func(a, b float64) float64 { return a / b }, mergeKeys)
+func (c *Counter[V]) Divide(o *Counter[V]) {
+	c.operate(o, numDiv[V])
 }
 
 // Apply a function to every value in the counter (including the
 // default)
-func (c *Counter) Apply(op func(k *string, a float64) float64) {
+func (c *Counter[V]) Apply(op func(k *string, a V) V) {
 	c.Base = op(nil, c.Base)
 
-	for k, v := range c.values {
-		c.Set(k, op(&k, v))
+	for _, e := range c.entries() {
+		// e is reused across iterations, so &e.key would alias the same
+		// address on every call; copy key into a fresh variable so a op
+		// that stashes the pointer (e.g. ArgMax) doesn't see it change
+		// out from under it once the loop moves on.
+		k := e.key
+		c.Set(k, op(&k, e.val))
 	}
 }
 
-// Log every value in the counter (including the default)
-func (c *Counter) Log() {
-	c.Apply(func(s *string, f float64) float64 { return math.Log(f) })
+// Log every value in the counter (including the default). Meaningful
+// only for Counter[float64]; on other V, values round-trip through
+// float64 and lose precision.
+func (c *Counter[V]) Log() {
+	c.Apply(func(s *string, f V) V { return fromFloat64[V](math.Log(toFloat64(f))) })
 }
 
-// Exponentiate every value in the counter (including the default)
-func (c *Counter) Exp() {
-	c.Apply(func(s *string, f float64) float64 { return math.Exp(f) })
+// Exponentiate every value in the counter (including the default).
+// Meaningful only for Counter[float64]; on other V, values round-trip
+// through float64 and lose precision.
+func (c *Counter[V]) Exp() {
+	c.Apply(func(s *string, f V) V { return fromFloat64[V](math.Exp(toFloat64(f))) })
 }
 
 // Reduce over the values in the counter (not including the default
 // value)
-func (c *Counter) reduce(base float64, op func(a, b float64) float64) float64 {
+func (c *Counter[V]) reduce(base V, op func(a, b V) V) V {
 	val := base
 
-	for _, v := range c.values {
-		val = op(val, v)
+	for _, e := range c.entries() {
+		val = op(val, e.val)
 	}
 
 	return val
 }
 
-func (c *Counter) ArgMax() (string, float64) {
+func (c *Counter[V]) ArgMax() (string, V) {
 	var maxKey *string = nil
-	maxVal := 0.0
+	var maxVal V
 
-	c.Apply(func (key *string, val float64) float64 {
-		if val > maxVal || maxKey == nil {
+	c.Apply(func(key *string, val V) V {
+		if maxKey == nil || numLess(maxVal, val) {
 			maxKey = key
 			maxVal = val
 		}
@@ -186,27 +377,29 @@ func (c *Counter) ArgMax() (string, float64) {
 		return val
 	})
 
-	return *maxKey, maxVal
+	return *maxKey, numCopy(maxVal)
 }
 
-func (c *Counter) Sum() float64 {
-	return c.reduce(c.Base, func (a, b float64) float64 { return a + b })
+func (c *Counter[V]) Sum() V {
+	return c.reduce(c.Base, numAdd[V])
 }
 
-// Normalize a counter s.t. the sum over values is now 1.0
-func (c *Counter) Normalize() {
-	sum := c.reduce(0.0, func(a, b float64) float64 { return a + b })
-	c.Apply(func(s *string, a float64) float64 { return a / sum })
+// Normalize a counter s.t. the sum over values is now 1.0. Meaningful
+// for Counter[float64] and Counter[*big.Float]; on int64 this divides
+// in place with integer truncation.
+func (c *Counter[V]) Normalize() {
+	sum := c.reduce(numZero[V](), numAdd[V])
+	c.Apply(func(s *string, a V) V { return numDiv(a, sum) })
 }
 
 // Special case of normalize - normalize a distribution and turn it
 // into a log-distribution (performing the normalization after the
-// xform to maintain precision)
-func (c *Counter) LogNormalize() {
-	sum := c.reduce(0.0, func(a, b float64) float64 { return a + b })
-	logSum := math.Log(sum)
+// xform to maintain precision). Meaningful only for Counter[float64].
+func (c *Counter[V]) LogNormalize() {
+	sum := c.reduce(numZero[V](), numAdd[V])
+	logSum := math.Log(toFloat64(sum))
 
-	c.Apply(func(s *string, a float64) float64 { return math.Log(a) - logSum })
+	c.Apply(func(s *string, a V) V { return fromFloat64[V](math.Log(toFloat64(a)) - logSum) })
 }
 
-var _ gnlp.Counter = New(0.0)
+var _ gnlp.Counter = New[float64](0.0)
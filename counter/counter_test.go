@@ -0,0 +1,116 @@
+package counter
+
+import "testing"
+
+func TestDensifyPreservesValues(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Densify()
+
+	if got := c.Get("a"); got != 1 {
+		t.Fatalf("Get(a) after Densify = %v, want 1", got)
+	}
+	if got := c.Get("b"); got != 2 {
+		t.Fatalf("Get(b) after Densify = %v, want 2", got)
+	}
+}
+
+func TestInternBeforeAnyValuesShared(t *testing.T) {
+	dict := NewStringDict()
+	dict.Intern("a")
+
+	c := New[float64](0)
+	c.Intern(dict)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Densify()
+
+	// c.Densify() interns its own keys into dict, so "a"'s id from
+	// before Intern was called is preserved
+	if id, ok := dict.Lookup("a"); !ok || id != 0 {
+		t.Fatalf("dict.Lookup(a) = %v, %v; want 0, true", id, ok)
+	}
+	if got := c.Get("a"); got != 1 {
+		t.Fatalf("Get(a) = %v, want 1", got)
+	}
+}
+
+func TestInternAfterDensifyRemapsValues(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Densify()
+
+	if got := c.Get("a"); got != 1 {
+		t.Fatalf("Get(a) before re-Intern = %v, want 1", got)
+	}
+
+	// A fresh dict that already has other keys interned ahead of "a",
+	// so re-Interning must remap c's dense values to the new ids rather
+	// than reusing the old ones.
+	dict := NewStringDict()
+	dict.Intern("z")
+	c.Intern(dict)
+
+	if got := c.Get("a"); got != 1 {
+		t.Fatalf("Get(a) after Intern on a densified counter = %v, want 1 (value lost)", got)
+	}
+}
+
+func TestIntBackend(t *testing.T) {
+	c := New[int64](0)
+	c.Set("a", 3)
+	c.Incr("a")
+
+	if got := c.Get("a"); got != 4 {
+		t.Fatalf("Get(a) = %v, want 4", got)
+	}
+}
+
+func TestOperateSharedDict(t *testing.T) {
+	dict := NewStringDict()
+
+	a := New[float64](0)
+	a.Intern(dict)
+	a.Set("x", 1)
+	a.Densify()
+
+	b := New[float64](0)
+	b.Intern(dict)
+	b.Set("x", 2)
+	b.Set("y", 3)
+	b.Densify()
+
+	sum := Add(a, b)
+	if got := sum.Get("x"); got != 3 {
+		t.Fatalf("Add: x = %v, want 3", got)
+	}
+	if got := sum.Get("y"); got != 3 {
+		t.Fatalf("Add: y = %v, want 3", got)
+	}
+}
+
+func TestArgMax(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 5)
+	c.Set("c", 3)
+
+	key, val := c.ArgMax()
+	if key != "b" || val != 5 {
+		t.Fatalf("ArgMax() = %q, %v; want %q, 5", key, val, "b")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 3)
+
+	c.Normalize()
+
+	if got := c.Sum(); got != 1 {
+		t.Fatalf("Sum() after Normalize = %v, want 1", got)
+	}
+}
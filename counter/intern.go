@@ -0,0 +1,44 @@
+package counter
+
+// StringDict interns strings to small integer ids so that multiple
+// counters built against the same dictionary can share an id space and
+// run element-wise ops (see operate) as a single indexed loop instead
+// of iterating merged key sets.
+type StringDict struct {
+	ids  map[string]uint32
+	keys []string
+}
+
+// NewStringDict builds an empty dictionary
+func NewStringDict() *StringDict {
+	return &StringDict{ids: make(map[string]uint32)}
+}
+
+// Intern returns s's id, allocating a new one if s hasn't been seen
+func (d *StringDict) Intern(s string) uint32 {
+	if id, ok := d.ids[s]; ok {
+		return id
+	}
+
+	id := uint32(len(d.keys))
+	d.keys = append(d.keys, s)
+	d.ids[s] = id
+
+	return id
+}
+
+// Lookup returns s's id without allocating one
+func (d *StringDict) Lookup(s string) (uint32, bool) {
+	id, ok := d.ids[s]
+	return id, ok
+}
+
+// Key returns the string interned at id
+func (d *StringDict) Key(id uint32) string {
+	return d.keys[id]
+}
+
+// Len returns the number of interned strings
+func (d *StringDict) Len() int {
+	return len(d.keys)
+}
@@ -0,0 +1,184 @@
+package counter
+
+import "math/big"
+
+// Numeric constrains the value type a Counter can hold. *big.Float has
+// no operator-based arithmetic (+, -, *, /, <), so every op that needs
+// to work across int64, float64 and *big.Float dispatches through the
+// num* helpers below instead of native operators. Since *big.Float is
+// the idiomatic type to mutate in place (v.Add(v, x)), Counter always
+// hands callers a fresh copy (see numCopy) and never the pointer backing
+// its own storage or its Base.
+type Numeric interface {
+	int64 | float64 | *big.Float
+}
+
+// numAdd, numSub, numMul and numDiv implement the four basic Counter
+// ops for every type in Numeric, routing *big.Float through its
+// method-based arithmetic instead of native operators.
+func numAdd[V Numeric](a, b V) V {
+	switch x := any(a).(type) {
+	case int64:
+		return any(x + any(b).(int64)).(V)
+	case float64:
+		return any(x + any(b).(float64)).(V)
+	case *big.Float:
+		return any(new(big.Float).Add(x, any(b).(*big.Float))).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+func numSub[V Numeric](a, b V) V {
+	switch x := any(a).(type) {
+	case int64:
+		return any(x - any(b).(int64)).(V)
+	case float64:
+		return any(x - any(b).(float64)).(V)
+	case *big.Float:
+		return any(new(big.Float).Sub(x, any(b).(*big.Float))).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+func numMul[V Numeric](a, b V) V {
+	switch x := any(a).(type) {
+	case int64:
+		return any(x * any(b).(int64)).(V)
+	case float64:
+		return any(x * any(b).(float64)).(V)
+	case *big.Float:
+		return any(new(big.Float).Mul(x, any(b).(*big.Float))).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+func numDiv[V Numeric](a, b V) V {
+	switch x := any(a).(type) {
+	case int64:
+		return any(x / any(b).(int64)).(V)
+	case float64:
+		return any(x / any(b).(float64)).(V)
+	case *big.Float:
+		return any(new(big.Float).Quo(x, any(b).(*big.Float))).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// numEqual reports whether a and b hold the same value. *big.Float is a
+// pointer type, so a plain == would compare identity instead of value.
+func numEqual[V Numeric](a, b V) bool {
+	switch x := any(a).(type) {
+	case int64:
+		return x == any(b).(int64)
+	case float64:
+		return x == any(b).(float64)
+	case *big.Float:
+		return x.Cmp(any(b).(*big.Float)) == 0
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// numLess reports whether a < b
+func numLess[V Numeric](a, b V) bool {
+	switch x := any(a).(type) {
+	case int64:
+		return x < any(b).(int64)
+	case float64:
+		return x < any(b).(float64)
+	case *big.Float:
+		return x.Cmp(any(b).(*big.Float)) < 0
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// numOne returns the multiplicative-identity-sized increment (1) for V,
+// used by Incr
+func numOne[V Numeric]() V {
+	var zero V
+	switch any(zero).(type) {
+	case int64:
+		return any(int64(1)).(V)
+	case float64:
+		return any(float64(1)).(V)
+	case *big.Float:
+		return any(big.NewFloat(1)).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// numZero returns the additive identity (0) for V. Unlike the Go zero
+// value of V, this is never a nil *big.Float, so it's safe to fold
+// numAdd over starting from numZero.
+func numZero[V Numeric]() V {
+	var zero V
+	switch any(zero).(type) {
+	case int64:
+		return any(int64(0)).(V)
+	case float64:
+		return any(float64(0)).(V)
+	case *big.Float:
+		return any(big.NewFloat(0)).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// toFloat64 converts v to a float64, lossily for *big.Float and for
+// int64 magnitudes beyond float64's exact integer range. Used by the
+// float64-oriented features (sampling, TopK/Quantile, KZ smoothing)
+// that are meaningful mainly for Counter[float64].
+func toFloat64[V Numeric](v V) float64 {
+	switch x := any(v).(type) {
+	case int64:
+		return float64(x)
+	case float64:
+		return x
+	case *big.Float:
+		f, _ := x.Float64()
+		return f
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// fromFloat64 converts f to V, the inverse of toFloat64
+func fromFloat64[V Numeric](f float64) V {
+	var zero V
+	switch any(zero).(type) {
+	case int64:
+		return any(int64(f)).(V)
+	case float64:
+		return any(f).(V)
+	case *big.Float:
+		return any(big.NewFloat(f)).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
+
+// numCopy returns a value holding the same number as v but, for
+// *big.Float, backed by distinct storage. int64 and float64 are already
+// copied by value on assignment, so they pass through unchanged. Used
+// everywhere a V crosses the Counter/caller boundary (Get, Set, New, and
+// the dense backend's padding) so that a caller mutating *big.Float
+// in place - the idiomatic way to use it - can never reach a Counter's
+// internal storage, including its shared Base.
+func numCopy[V Numeric](v V) V {
+	switch x := any(v).(type) {
+	case int64:
+		return v
+	case float64:
+		return v
+	case *big.Float:
+		return any(new(big.Float).Set(x)).(V)
+	default:
+		panic("counter: unsupported Numeric type")
+	}
+}
@@ -0,0 +1,179 @@
+package counter
+
+import "math"
+import "math/rand"
+
+// Lazily built Walker/Vose alias table for O(1) categorical sampling.
+// Rebuilt on first Sample call after a write invalidates it.
+type aliasTable struct {
+	valid bool
+	keys  []string
+	prob  []float64
+	alias []int
+}
+
+// Build (or rebuild) the alias table from the counter's current keys,
+// exponentiating values first if the counter is in log-space.
+func (c *Counter[V]) buildAlias() {
+	entries := c.entries()
+	n := len(entries)
+
+	keys := make([]string, n)
+	weights := make([]float64, n)
+	sum := 0.0
+
+	for i, e := range entries {
+		keys[i] = e.key
+
+		w := toFloat64(e.val)
+		if c.LogSpace {
+			w = math.Exp(w)
+		}
+		weights[i] = w
+		sum += w
+	}
+
+	prob := make([]float64, n)
+	alias := make([]int, n)
+
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+
+	for i, w := range weights {
+		prob[i] = w * float64(n) / sum
+		if prob[i] < 1.0 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		alias[s] = l
+		prob[l] = prob[l] - (1.0 - prob[s])
+
+		if prob[l] < 1.0 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	// Leftover entries are numerically ~1.0 due to floating point error
+	for _, i := range large {
+		prob[i] = 1.0
+	}
+	for _, i := range small {
+		prob[i] = 1.0
+	}
+
+	c.alias = aliasTable{valid: true, keys: keys, prob: prob, alias: alias}
+}
+
+// Draw a single key from the alias table, building it first if stale.
+// Reports false if the counter has no tracked keys to draw from.
+func (c *Counter[V]) drawAlias(r *rand.Rand) (string, bool) {
+	if !c.alias.valid {
+		c.buildAlias()
+	}
+
+	if len(c.alias.keys) == 0 {
+		return "", false
+	}
+
+	i := r.Intn(len(c.alias.keys))
+
+	if r.Float64() < c.alias.prob[i] {
+		return c.alias.keys[i], true
+	}
+	return c.alias.keys[c.alias.alias[i]], true
+}
+
+// Sample draws a key, treating the counter's values as unnormalized
+// probabilities (or log-probabilities, if LogSpace is set). Repeated
+// calls against a fixed distribution are O(1) after the first, via a
+// lazily built alias table. Reports false if the counter has no tracked
+// keys.
+func (c *Counter[V]) Sample(r *rand.Rand) (string, bool) {
+	return c.drawAlias(r)
+}
+
+// SampleN draws up to n keys independently via Sample, stopping early
+// (returning fewer than n) if the counter has no tracked keys.
+func (c *Counter[V]) SampleN(n int, r *rand.Rand) []string {
+	result := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		k, ok := c.Sample(r)
+		if !ok {
+			break
+		}
+		result = append(result, k)
+	}
+
+	return result
+}
+
+// EpsilonGreedy returns ArgMax with probability 1-epsilon, and a
+// uniform random key otherwise. Reports false if the counter has no
+// tracked keys.
+func (c *Counter[V]) EpsilonGreedy(epsilon float64, r *rand.Rand) (string, bool) {
+	keys := c.Keys()
+	if len(keys) == 0 {
+		return "", false
+	}
+
+	if r.Float64() >= epsilon {
+		key, _ := c.ArgMax()
+		return key, true
+	}
+
+	return keys[r.Intn(len(keys))], true
+}
+
+// SoftmaxSample draws a key after rescaling values by 1/temperature
+// (in log-space if LogSpace is set, otherwise by raising values to the
+// 1/temperature power). This bypasses the cached alias table, since the
+// rescaled distribution is specific to the given temperature. Reports
+// false if the counter has no tracked keys.
+func (c *Counter[V]) SoftmaxSample(temperature float64, r *rand.Rand) (string, bool) {
+	entries := c.entries()
+	if len(entries) == 0 {
+		return "", false
+	}
+
+	keys := make([]string, len(entries))
+	weights := make([]float64, len(entries))
+	sum := 0.0
+
+	for i, e := range entries {
+		keys[i] = e.key
+
+		v := toFloat64(e.val)
+		var w float64
+		if c.LogSpace {
+			w = math.Exp(v / temperature)
+		} else {
+			w = math.Pow(v, 1.0/temperature)
+		}
+		weights[i] = w
+		sum += w
+	}
+
+	target := r.Float64() * sum
+	acc := 0.0
+
+	for i, w := range weights {
+		acc += w
+		if acc >= target {
+			return keys[i], true
+		}
+	}
+
+	return keys[len(keys)-1], true
+}
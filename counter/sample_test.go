@@ -0,0 +1,72 @@
+package counter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSampleEmptyCounter(t *testing.T) {
+	c := New[float64](0)
+	r := rand.New(rand.NewSource(1))
+
+	if _, ok := c.Sample(r); ok {
+		t.Fatalf("Sample on empty counter: got ok=true, want false")
+	}
+	if got := c.SampleN(5, r); len(got) != 0 {
+		t.Fatalf("SampleN on empty counter: got %v, want empty", got)
+	}
+	if _, ok := c.EpsilonGreedy(0.5, r); ok {
+		t.Fatalf("EpsilonGreedy on empty counter: got ok=true, want false")
+	}
+	if _, ok := c.SoftmaxSample(1.0, r); ok {
+		t.Fatalf("SoftmaxSample on empty counter: got ok=true, want false")
+	}
+}
+
+func TestSampleDrawsTrackedKeys(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 3)
+	r := rand.New(rand.NewSource(1))
+
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		k, ok := c.Sample(r)
+		if !ok {
+			t.Fatalf("Sample: got ok=false, want true")
+		}
+		if k != "a" && k != "b" {
+			t.Fatalf("Sample returned untracked key %q", k)
+		}
+		seen[k] = true
+	}
+	if len(seen) != 2 {
+		t.Fatalf("Sample over 50 draws only ever returned %v, expected both keys eventually", seen)
+	}
+}
+
+func TestEpsilonGreedyExploitsArgMax(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 3)
+	r := rand.New(rand.NewSource(1))
+
+	k, ok := c.EpsilonGreedy(0, r)
+	if !ok || k != "b" {
+		t.Fatalf("EpsilonGreedy(0, ...) = %q, %v; want %q, true", k, ok, "b")
+	}
+}
+
+func TestSoftmaxSampleReturnsTrackedKey(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10; i++ {
+		k, ok := c.SoftmaxSample(0.5, r)
+		if !ok || (k != "a" && k != "b") {
+			t.Fatalf("SoftmaxSample = %q, %v; want a tracked key, true", k, ok)
+		}
+	}
+}
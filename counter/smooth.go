@@ -0,0 +1,176 @@
+package counter
+
+import "math"
+import "sort"
+import "strconv"
+
+// kzPoint is a single (position, value) sample used internally by
+// SmoothKZ and SmoothKZA.
+type kzPoint struct {
+	key   string
+	order float64
+	value float64
+}
+
+// orderedPoints returns the counter's tracked entries as ordered
+// samples, sorted by position. Positions come from KeyOrder if set, or
+// from parsing the key as a float64 otherwise. Meaningful only for
+// Counter[float64]; SmoothKZ/SmoothKZA are the only callers.
+func (c *Counter[V]) orderedPoints() []kzPoint {
+	entries := c.entries()
+	points := make([]kzPoint, 0, len(entries))
+
+	for _, e := range entries {
+		var order float64
+		if c.KeyOrder != nil {
+			order = c.KeyOrder(e.key)
+		} else {
+			order, _ = strconv.ParseFloat(e.key, 64)
+		}
+
+		points = append(points, kzPoint{e.key, order, toFloat64(e.val)})
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].order < points[j].order })
+
+	return points
+}
+
+// pointsToCounter turns a slice of smoothed samples back into a
+// Counter, preserving the default value
+func (c *Counter[V]) pointsToCounter(points []kzPoint) *Counter[V] {
+	result := New(c.Base)
+	for _, p := range points {
+		result.Set(p.key, fromFloat64[V](p.value))
+	}
+	return result
+}
+
+// windowAverage averages the values of points whose position falls
+// within [center-leftH, center+rightH], treating missing integer
+// positions in that range as Base when fillBase is set. Positions
+// beyond the first or last point are never filled, so the window still
+// shrinks at the endpoints.
+func windowAverage(points []kzPoint, i int, leftH, rightH float64, fillBase bool, base float64) float64 {
+	center := points[i].order
+	lo := center - leftH
+	hi := center + rightH
+
+	sum := 0.0
+	count := 0
+
+	for j := i; j >= 0 && points[j].order >= lo; j-- {
+		sum += points[j].value
+		count++
+	}
+	for j := i + 1; j < len(points) && points[j].order <= hi; j++ {
+		sum += points[j].value
+		count++
+	}
+
+	if fillBase {
+		flo := math.Max(lo, points[0].order)
+		fhi := math.Min(hi, points[len(points)-1].order)
+		span := int(math.Round(fhi)) - int(math.Round(flo)) + 1
+		if span > count {
+			sum += base * float64(span-count)
+			count = span
+		}
+	}
+
+	if count == 0 {
+		return base
+	}
+
+	return sum / float64(count)
+}
+
+// kzRadii splits a window of size m into left/right radii spanning m
+// unit-spaced points around (and including) the center. For odd m this
+// is the usual symmetric (m-1)/2 on each side; for even m the extra
+// point goes to the left, since a window of an even number of points
+// can't be centered exactly.
+func kzRadii(m int) (leftH, rightH float64) {
+	half := float64(m-1) / 2.0
+	return math.Ceil(half), math.Floor(half)
+}
+
+// kzPass runs one centered moving-average pass of window size m over
+// points, shrinking the window at the endpoints
+func kzPass(points []kzPoint, m int, fillBase bool, base float64) []kzPoint {
+	leftH, rightH := kzRadii(m)
+	result := make([]kzPoint, len(points))
+
+	for i, p := range points {
+		result[i] = kzPoint{p.key, p.order, windowAverage(points, i, leftH, rightH, fillBase, base)}
+	}
+
+	return result
+}
+
+// SmoothKZ applies the Kolmogorov-Zurbenko filter: k successive passes
+// of a centered moving average of window size m, over keys ordered by
+// KeyOrder (or by parsing keys as float64). Gaps in the key sequence
+// are skipped unless FillBase is set.
+func (c *Counter[V]) SmoothKZ(m int, k int) *Counter[V] {
+	points := c.orderedPoints()
+
+	for pass := 0; pass < k; pass++ {
+		points = kzPass(points, m, c.FillBase, toFloat64(c.Base))
+	}
+
+	return c.pointsToCounter(points)
+}
+
+// diff returns the difference between the value m/2 positions away
+// from i (in direction dir, +1 forward or -1 backward) and the value
+// at i, clamping at the ends of the series
+func diff(points []kzPoint, i, span, dir int) float64 {
+	j := i + dir*span
+
+	if j < 0 {
+		j = 0
+	}
+	if j >= len(points) {
+		j = len(points) - 1
+	}
+
+	return points[j].value - points[i].value
+}
+
+// SmoothKZA is the adaptive variant of SmoothKZ: near breakpoints,
+// detected by forward/backward differences of a once-KZ-smoothed
+// series exceeding eps, the averaging window is truncated on the side
+// with the larger jump before re-averaging.
+func (c *Counter[V]) SmoothKZA(m int, k int, eps float64) *Counter[V] {
+	points := c.orderedPoints()
+	kz1 := kzPass(points, m, c.FillBase, toFloat64(c.Base))
+
+	span := m / 2
+	baseLeftH, baseRightH := kzRadii(m)
+
+	for pass := 0; pass < k; pass++ {
+		next := make([]kzPoint, len(points))
+
+		for i, p := range points {
+			fwd := diff(kz1, i, span, 1)
+			back := diff(kz1, i, span, -1)
+
+			leftH, rightH := baseLeftH, baseRightH
+
+			if math.Abs(fwd) > eps || math.Abs(back) > eps {
+				if math.Abs(fwd) > math.Abs(back) {
+					rightH = math.Max(0, baseRightH-(math.Abs(fwd)-eps))
+				} else {
+					leftH = math.Max(0, baseLeftH-(math.Abs(back)-eps))
+				}
+			}
+
+			next[i] = kzPoint{p.key, p.order, windowAverage(points, i, leftH, rightH, c.FillBase, toFloat64(c.Base))}
+		}
+
+		points = next
+	}
+
+	return c.pointsToCounter(points)
+}
@@ -0,0 +1,92 @@
+package counter
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSmoothKZConstantSeries(t *testing.T) {
+	c := New[float64](0)
+	for i := 0; i < 10; i++ {
+		c.Set(strconv.Itoa(i), 5)
+	}
+
+	smoothed := c.SmoothKZ(3, 2)
+	for i := 0; i < 10; i++ {
+		if got := smoothed.Get(strconv.Itoa(i)); got != 5 {
+			t.Fatalf("SmoothKZ on a constant series at %d = %v, want 5", i, got)
+		}
+	}
+}
+
+func TestSmoothKZSkipsGapsByDefault(t *testing.T) {
+	c := New[float64](0)
+	c.Set("0", 1)
+	c.Set("2", 3)
+	// "1" is a gap: not zero-filled, so it contributes nothing to the
+	// average even though it falls inside the window
+
+	smoothed := c.SmoothKZ(5, 1)
+	if got := smoothed.Get("0"); got != 2 {
+		t.Fatalf("SmoothKZ at 0 (gap skipped) = %v, want 2", got)
+	}
+	if got := smoothed.Get("2"); got != 2 {
+		t.Fatalf("SmoothKZ at 2 (gap skipped) = %v, want 2", got)
+	}
+}
+
+func TestSmoothKZAFillBase(t *testing.T) {
+	c := New[float64](0)
+	c.Set("0", 3)
+	c.Set("2", 3)
+	c.FillBase = true
+
+	smoothed := c.SmoothKZA(3, 1, 100)
+	// with FillBase, the missing "1" position counts as Base (0) in the
+	// window average, pulling the result below the unfilled case
+	if got := smoothed.Get("0"); got >= 3 {
+		t.Fatalf("SmoothKZA with FillBase at 0 = %v, want < 3", got)
+	}
+}
+
+func TestSmoothKZAPreservesKeys(t *testing.T) {
+	c := New[float64](0)
+	c.Set("0", 1)
+	c.Set("1", 100)
+	c.Set("2", 1)
+
+	smoothed := c.SmoothKZA(3, 2, 0.01)
+	got := smoothed.Keys()
+	if len(got) != 3 {
+		t.Fatalf("SmoothKZA returned keys %v, want 3 keys (0, 1, 2)", got)
+	}
+}
+
+func TestSmoothKZEvenWindowSpansMPoints(t *testing.T) {
+	c := New[float64](0)
+	for i := 0; i <= 9; i++ {
+		c.Set(strconv.Itoa(i), float64(i))
+	}
+
+	// m=4 at an interior point must average 4 points (3,4,5,6), not 3.
+	smoothed := c.SmoothKZ(4, 1)
+	if got := smoothed.Get("5"); got != 4.5 {
+		t.Fatalf("SmoothKZ(4, 1) at 5 = %v, want 4.5 (avg of 3,4,5,6)", got)
+	}
+}
+
+func TestSmoothKZUsesKeyOrder(t *testing.T) {
+	c := New[float64](0)
+	order := map[string]float64{"first": 0, "second": 1, "third": 2}
+	c.KeyOrder = func(k string) float64 { return order[k] }
+
+	c.Set("first", 1)
+	c.Set("second", 5)
+	c.Set("third", 1)
+
+	smoothed := c.SmoothKZ(3, 1)
+	if got := smoothed.Get("second"); got <= 1 {
+		t.Fatalf("SmoothKZ with KeyOrder at \"second\" = %v, want > 1 (averaged with neighbors)", got)
+	}
+}
+
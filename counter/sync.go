@@ -0,0 +1,106 @@
+package counter
+
+import "sync"
+import "unsafe"
+
+// SyncCounter wraps a Counter with a mutex so that parallel feature
+// extractors and other streaming producers can accumulate into a
+// shared counter without each call site managing its own locking.
+type SyncCounter struct {
+	mu sync.RWMutex
+	c  *Counter[float64]
+}
+
+// NewConcurrent builds an empty, thread-safe counter with the given
+// default value
+func NewConcurrent(base float64) *SyncCounter {
+	return &SyncCounter{c: New(base)}
+}
+
+// IncrBy adds delta to the value for k
+func (s *SyncCounter) IncrBy(k string, delta float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.c.Set(k, s.c.Get(k)+delta)
+}
+
+// UpdateIfGt sets k to v if v is greater than the current value
+func (s *SyncCounter) UpdateIfGt(k string, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v > s.c.Get(k) {
+		s.c.Set(k, v)
+	}
+}
+
+// UpdateIfLt sets k to v if v is less than the current value
+func (s *SyncCounter) UpdateIfLt(k string, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v < s.c.Get(k) {
+		s.c.Set(k, v)
+	}
+}
+
+// CompareAndSwap sets k to new if its current value is old, reporting
+// whether the swap took place
+func (s *SyncCounter) CompareAndSwap(k string, old, new float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.c.Get(k) != old {
+		return false
+	}
+
+	s.c.Set(k, new)
+	return true
+}
+
+// Merge adds other into this counter under a single lock
+func (s *SyncCounter) Merge(other *Counter[float64]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.c.Add(other)
+}
+
+// MergeSync adds other into this counter, locking both counters in a
+// deterministic order (by address) so that concurrent merges in either
+// direction can't deadlock
+func (s *SyncCounter) MergeSync(other *SyncCounter) {
+	if s == other {
+		return
+	}
+
+	first, second := s, other
+	if uintptr(unsafe.Pointer(s)) > uintptr(unsafe.Pointer(other)) {
+		first, second = other, s
+	}
+
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	s.c.Add(other.c)
+}
+
+// Snapshot returns an independent copy of the counter for consumers
+// (ArgMax, Normalize, ...) while writers continue against the original.
+// The copy is an ordinary, unlocked Counter: writes to it are not
+// prevented, and they never reach the original (nor do later writes to
+// the original reach the copy).
+func (s *SyncCounter) Snapshot() *Counter[float64] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := New(s.c.Base)
+	for _, k := range s.c.Keys() {
+		result.Set(k, s.c.Get(k))
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package counter
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncCounterIncrByConcurrent(t *testing.T) {
+	s := NewConcurrent(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.IncrBy("k", 1)
+		}()
+	}
+	wg.Wait()
+
+	if got := s.Snapshot().Get("k"); got != 100 {
+		t.Fatalf("IncrBy x100 concurrently: got %v, want 100", got)
+	}
+}
+
+func TestSyncCounterUpdateIfGtLt(t *testing.T) {
+	s := NewConcurrent(0)
+
+	s.UpdateIfGt("k", 5)
+	s.UpdateIfGt("k", 3) // should not lower it
+	if got := s.Snapshot().Get("k"); got != 5 {
+		t.Fatalf("UpdateIfGt: got %v, want 5", got)
+	}
+
+	s.UpdateIfLt("k", 2)
+	s.UpdateIfLt("k", 9) // should not raise it
+	if got := s.Snapshot().Get("k"); got != 2 {
+		t.Fatalf("UpdateIfLt: got %v, want 2", got)
+	}
+}
+
+func TestSyncCounterCompareAndSwap(t *testing.T) {
+	s := NewConcurrent(0)
+	s.IncrBy("k", 5)
+
+	if s.CompareAndSwap("k", 1, 9) {
+		t.Fatalf("CompareAndSwap with stale old value succeeded")
+	}
+	if !s.CompareAndSwap("k", 5, 9) {
+		t.Fatalf("CompareAndSwap with matching old value failed")
+	}
+	if got := s.Snapshot().Get("k"); got != 9 {
+		t.Fatalf("after CompareAndSwap: got %v, want 9", got)
+	}
+}
+
+func TestSyncCounterMerge(t *testing.T) {
+	s := NewConcurrent(0)
+	s.IncrBy("a", 1)
+
+	other := New[float64](0)
+	other.Set("a", 2)
+	other.Set("b", 3)
+
+	s.Merge(other)
+
+	snap := s.Snapshot()
+	if got := snap.Get("a"); got != 3 {
+		t.Fatalf("Merge: a = %v, want 3", got)
+	}
+	if got := snap.Get("b"); got != 3 {
+		t.Fatalf("Merge: b = %v, want 3", got)
+	}
+}
+
+func TestSyncCounterMergeSyncDeadlockFree(t *testing.T) {
+	a := NewConcurrent(0)
+	b := NewConcurrent(0)
+	a.IncrBy("x", 1)
+	b.IncrBy("x", 2)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a.MergeSync(b)
+	}()
+	go func() {
+		defer wg.Done()
+		b.MergeSync(a)
+	}()
+	wg.Wait()
+
+	if got := a.Snapshot().Get("x"); got < 3 {
+		t.Fatalf("MergeSync: a.x = %v, want at least 3", got)
+	}
+}
+
+func TestSyncCounterSnapshotIsIndependent(t *testing.T) {
+	s := NewConcurrent(0)
+	s.IncrBy("k", 1)
+
+	snap := s.Snapshot()
+	s.IncrBy("k", 1)
+
+	if got := snap.Get("k"); got != 1 {
+		t.Fatalf("Snapshot mutated by later writes: got %v, want 1", got)
+	}
+}
@@ -0,0 +1,141 @@
+package counter
+
+import "container/heap"
+import "sort"
+
+// Entry is a single key/value pair as returned by TopK and BottomK
+type Entry struct {
+	Key   string
+	Value float64
+}
+
+// entryHeap is a container/heap of Entry, ordered by less; used as a
+// bounded min-heap (for TopK) or max-heap (for BottomK) depending on
+// the comparator supplied.
+type entryHeap struct {
+	entries []Entry
+	less    func(a, b float64) bool
+}
+
+func (h entryHeap) Len() int            { return len(h.entries) }
+func (h entryHeap) Less(i, j int) bool  { return h.less(h.entries[i].Value, h.entries[j].Value) }
+func (h entryHeap) Swap(i, j int)       { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *entryHeap) Push(x interface{}) { h.entries = append(h.entries, x.(Entry)) }
+
+func (h *entryHeap) Pop() interface{} {
+	old := h.entries
+	n := len(old)
+	e := old[n-1]
+	h.entries = old[:n-1]
+	return e
+}
+
+// bounded runs a size-k bounded heap over the counter's entries, using
+// less to decide which entries the heap keeps
+func (c *Counter[V]) bounded(k int, less func(a, b float64) bool) []Entry {
+	if k <= 0 {
+		return nil
+	}
+
+	h := &entryHeap{less: less}
+	heap.Init(h)
+
+	for _, e := range c.entries() {
+		v := toFloat64(e.val)
+
+		if h.Len() < k {
+			heap.Push(h, Entry{e.key, v})
+		} else if !less(v, h.entries[0].Value) {
+			heap.Pop(h)
+			heap.Push(h, Entry{e.key, v})
+		}
+	}
+
+	result := make([]Entry, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(Entry)
+	}
+
+	return result
+}
+
+// TopK returns the k entries with the largest values, descending
+func (c *Counter[V]) TopK(k int) []Entry {
+	return c.bounded(k, func(a, b float64) bool { return a < b })
+}
+
+// BottomK returns the k entries with the smallest values, ascending
+func (c *Counter[V]) BottomK(k int) []Entry {
+	return c.bounded(k, func(a, b float64) bool { return a > b })
+}
+
+// Quantile returns the value at which the q-th quantile (0..1) falls,
+// weighting every tracked key equally
+func (c *Counter[V]) Quantile(q float64) float64 {
+	return c.Percentiles(q)[0]
+}
+
+// Percentiles computes multiple quantiles in a single sort, weighting
+// every tracked key equally
+func (c *Counter[V]) Percentiles(qs ...float64) []float64 {
+	entries := c.entries()
+	values := make([]float64, 0, len(entries))
+
+	for _, e := range entries {
+		values = append(values, toFloat64(e.val))
+	}
+	sort.Float64s(values)
+
+	result := make([]float64, len(qs))
+	n := len(values)
+
+	for i, q := range qs {
+		if n == 0 {
+			result[i] = toFloat64(c.Base)
+			continue
+		}
+
+		idx := int(q * float64(n-1))
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= n {
+			idx = n - 1
+		}
+
+		result[i] = values[idx]
+	}
+
+	return result
+}
+
+// QuantileWeighted returns the value at which cumulative mass (treating
+// each key's value as its own frequency) reaches q
+func (c *Counter[V]) QuantileWeighted(q float64) float64 {
+	entries := c.entries()
+	values := make([]float64, 0, len(entries))
+	total := 0.0
+
+	for _, e := range entries {
+		v := toFloat64(e.val)
+		values = append(values, v)
+		total += v
+	}
+	sort.Float64s(values)
+
+	if total == 0 {
+		return toFloat64(c.Base)
+	}
+
+	target := q * total
+	acc := 0.0
+
+	for _, v := range values {
+		acc += v
+		if acc >= target {
+			return v
+		}
+	}
+
+	return values[len(values)-1]
+}
@@ -0,0 +1,94 @@
+package counter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestCounter() *Counter[float64] {
+	c := New[float64](0)
+	c.Set("a", 5)
+	c.Set("b", 1)
+	c.Set("c", 9)
+	c.Set("d", 3)
+	return c
+}
+
+func TestTopKNonPositiveK(t *testing.T) {
+	c := newTestCounter()
+
+	if got := c.TopK(0); got != nil {
+		t.Fatalf("TopK(0) = %v, want nil", got)
+	}
+	if got := c.TopK(-1); got != nil {
+		t.Fatalf("TopK(-1) = %v, want nil", got)
+	}
+	if got := c.BottomK(0); got != nil {
+		t.Fatalf("BottomK(0) = %v, want nil", got)
+	}
+}
+
+func TestTopKOrdering(t *testing.T) {
+	c := newTestCounter()
+
+	got := c.TopK(2)
+	want := []Entry{{"c", 9}, {"a", 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("TopK(2) = %v, want %v", got, want)
+	}
+}
+
+func TestBottomKOrdering(t *testing.T) {
+	c := newTestCounter()
+
+	got := c.BottomK(2)
+	want := []Entry{{"b", 1}, {"d", 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BottomK(2) = %v, want %v", got, want)
+	}
+}
+
+func TestTopKLargerThanCounter(t *testing.T) {
+	c := newTestCounter()
+
+	if got := c.TopK(10); len(got) != 4 {
+		t.Fatalf("TopK(10) over a 4-entry counter returned %d entries, want 4", len(got))
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Set("d", 4)
+
+	if got := c.Quantile(0); got != 1 {
+		t.Fatalf("Quantile(0) = %v, want 1", got)
+	}
+	if got := c.Quantile(1); got != 4 {
+		t.Fatalf("Quantile(1) = %v, want 4", got)
+	}
+}
+
+func TestPercentilesEmptyCounter(t *testing.T) {
+	c := New[float64](7)
+
+	got := c.Percentiles(0, 0.5, 1)
+	want := []float64{7, 7, 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Percentiles on empty counter = %v, want %v (the Base)", got, want)
+	}
+}
+
+func TestQuantileWeighted(t *testing.T) {
+	c := New[float64](0)
+	c.Set("a", 1)
+	c.Set("b", 1)
+	c.Set("c", 8)
+
+	// cumulative mass: 1, 2, 10 -> q=0.5 * 10 = 5 reached at value 8
+	if got := c.QuantileWeighted(0.5); got != 8 {
+		t.Fatalf("QuantileWeighted(0.5) = %v, want 8", got)
+	}
+}